@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// cacheWindowBefore/After bound the rolling window the cache keeps fresh:
+// a week of history for the day.ics/booking.ics lookups, plus two months
+// ahead for the utilization view's pagination.
+const (
+	cacheWindowBefore = -7 * 24 * time.Hour
+	cacheWindowAfter  = 60 * 24 * time.Hour
+)
+
+// CalendarCache periodically refreshes bookings for a rolling window from
+// the configured CalendarProvider and serves them from memory, so request
+// handlers never block on a live API call.
+type CalendarCache struct {
+	provider CalendarProvider
+	interval time.Duration
+
+	mu        sync.RWMutex
+	bookings  []Booking
+	lastFetch time.Time
+	lastErr   error
+}
+
+// NewCalendarCache builds a cache that refreshes from provider every
+// interval.
+func NewCalendarCache(provider CalendarProvider, interval time.Duration) *CalendarCache {
+	return &CalendarCache{provider: provider, interval: interval}
+}
+
+// Start performs an initial synchronous refresh, then refreshes on a
+// background ticker until ctx is cancelled.
+func (c *CalendarCache) Start(ctx context.Context) {
+	c.refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (c *CalendarCache) refresh(ctx context.Context) {
+	fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	bookings, err := c.provider.ListEvents(fetchCtx, now.Add(cacheWindowBefore), now.Add(cacheWindowAfter))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.lastErr = err
+		slog.Error("Failed to refresh calendar cache", "error", err)
+		return
+	}
+
+	c.bookings = bookings
+	c.lastFetch = now
+	c.lastErr = nil
+	slog.Info("Calendar cache refreshed", "count", len(bookings), "fetched_at", now)
+}
+
+// Bookings returns a snapshot of the cached bookings, the time of the last
+// successful fetch (zero if one hasn't happened yet), and the error from
+// the most recent refresh attempt, if any. A non-nil error alongside a
+// non-zero lastFetch means the cache is serving stale-but-usable data.
+func (c *CalendarCache) Bookings() (bookings []Booking, lastFetch time.Time, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Booking(nil), c.bookings...), c.lastFetch, c.lastErr
+}
+
+// filterBookingsInWindow returns the bookings that overlap [start, end).
+func filterBookingsInWindow(bookings []Booking, start, end time.Time) []Booking {
+	var out []Booking
+	for _, b := range bookings {
+		if b.StartTime.Before(end) && b.EndTime.After(start) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
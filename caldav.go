@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CalDAVConfig holds the connection details for a CalDAV-speaking calendar
+// (Nextcloud, Radicale, iCloud, ...).
+type CalDAVConfig struct {
+	URL      string `json:"url"` // Calendar collection URL, e.g. https://cloud.example.com/remote.php/dav/calendars/bob/tennis/
+	Username string `json:"username"`
+	Password string `json:"password"` // App password/token, not the account password
+}
+
+// CalDAVProvider implements CalendarProvider against a CalDAV calendar
+// collection, so the app can point at a Nextcloud/Radicale/iCloud calendar
+// without Google OAuth.
+type CalDAVProvider struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+func newCalDAVProvider(config CalendarConfig) (*CalDAVProvider, error) {
+	if config.CalDAV.URL == "" {
+		return nil, fmt.Errorf("caldav provider selected but caldav.url is not configured")
+	}
+	return &CalDAVProvider{
+		url:      config.CalDAV.URL,
+		username: config.CalDAV.Username,
+		password: config.CalDAV.Password,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// calendarQueryBody is a REPORT request restricting results to VEVENTs whose
+// time range overlaps [start, end).
+const calendarQueryBody = `<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:getetag />
+    <C:calendar-data />
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%s" end="%s" />
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+func (p *CalDAVProvider) ListEvents(ctx context.Context, start, end time.Time) ([]Booking, error) {
+	body := fmt.Sprintf(calendarQueryBody, start.UTC().Format("20060102T150405Z"), end.UTC().Format("20060102T150405Z"))
+
+	req, err := http.NewRequestWithContext(ctx, "REPORT", p.url, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to build CalDAV REPORT request: %v", err)
+	}
+	req.SetBasicAuth(p.username, p.password)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach CalDAV server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CalDAV server returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CalDAV response: %v", err)
+	}
+
+	bookings := parseICSBookings(data, start, end)
+	slog.Info("Bookings parsed from CalDAV response", "count", len(bookings))
+
+	return bookings, nil
+}
+
+// parseICSBookings extracts VEVENTs embedded in a CalDAV multistatus
+// response (or a raw ICS document) into Bookings, expanding any RRULE into
+// concrete occurrences starting within [windowStart, windowEnd] (both ends
+// inclusive).
+func parseICSBookings(data []byte, windowStart, windowEnd time.Time) []Booking {
+	var bookings []Booking
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rawLines []string
+	for scanner.Scan() {
+		rawLines = append(rawLines, scanner.Text())
+	}
+	lines := unfoldICSLines(rawLines)
+
+	var inEvent bool
+	var uid, summary, dtstartLine, dtendLine string
+	var rruleLines []string
+	var exdates []time.Time
+
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		switch {
+		case strings.HasPrefix(line, "BEGIN:VEVENT"):
+			inEvent = true
+			uid, summary, dtstartLine, dtendLine = "", "", "", ""
+			rruleLines, exdates = nil, nil
+		case strings.HasPrefix(line, "END:VEVENT"):
+			startTime, endTime, ok := parseICSTimes(dtstartLine, dtendLine)
+			if ok {
+				if summary == "" {
+					summary = "(No title)"
+				}
+				if len(rruleLines) > 0 {
+					bookings = append(bookings, expandRecurrence(uid, summary, startTime, endTime, rruleLines, exdates, windowStart, windowEnd)...)
+				} else {
+					bookings = append(bookings, Booking{
+						ID:        uid,
+						Title:     summary,
+						StartTime: startTime,
+						EndTime:   endTime,
+						Duration:  endTime.Sub(startTime),
+					})
+				}
+			}
+			inEvent = false
+		case !inEvent:
+			continue
+		case strings.HasPrefix(line, "UID:"):
+			uid = strings.TrimPrefix(line, "UID:")
+		case strings.HasPrefix(line, "SUMMARY:"):
+			summary = strings.TrimPrefix(line, "SUMMARY:")
+		case strings.HasPrefix(line, "DTSTART"):
+			dtstartLine = line
+		case strings.HasPrefix(line, "DTEND"):
+			dtendLine = line
+		case strings.HasPrefix(line, "RRULE:"):
+			rruleLines = append(rruleLines, line)
+		case strings.HasPrefix(line, "EXDATE"):
+			exdates = append(exdates, parseEXDATE(line)...)
+		}
+	}
+
+	return bookings
+}
+
+// icsTZIDAndValue splits an ICS "PROP;TZID=x;OTHER=y:VALUE" line into its
+// TZID parameter, if any, and the raw value after the final colon.
+func icsTZIDAndValue(line string) (tzid, value string) {
+	idx := strings.LastIndex(line, ":")
+	if idx == -1 {
+		return "", line
+	}
+	params, value := line[:idx], line[idx+1:]
+	for _, param := range strings.Split(params, ";") {
+		if rest, ok := strings.CutPrefix(param, "TZID="); ok {
+			tzid = rest
+		}
+	}
+	return tzid, value
+}
+
+// unfoldICSLines joins RFC 5545 continuation lines (a line starting with a
+// space or tab is a continuation of the previous property) back into the
+// single logical line they were folded from.
+func unfoldICSLines(lines []string) []string {
+	var unfolded []string
+	for _, line := range lines {
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(unfolded) > 0 {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+	return unfolded
+}
+
+func parseICSTimes(dtstartLine, dtendLine string) (time.Time, time.Time, bool) {
+	if dtstartLine == "" || dtendLine == "" {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start, err := parseICSDateTime(dtstartLine)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	end, err := parseICSDateTime(dtendLine)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return start, end, true
+}
+
+// parseICSDateTime parses a DTSTART/DTEND-style "PROP;TZID=x:VALUE" line,
+// resolving the TZID parameter (if present) so timed events from non-UTC
+// CalDAV servers land on the correct instant rather than being read as a
+// bare UTC timestamp.
+func parseICSDateTime(line string) (time.Time, error) {
+	tzid, value := icsTZIDAndValue(line)
+	if tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return parseICSTime(value)
+}
+
+// parseICSTime parses either a floating/UTC timestamp (20060102T150405Z or
+// 20060102T150405) or an all-day date (20060102).
+func parseICSTime(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102", value)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CalendarProvider abstracts over calendar backends (Google Calendar, CalDAV,
+// ...) so the rest of the app never talks to a specific API directly.
+type CalendarProvider interface {
+	// ListEvents returns bookings that overlap the window [start, end);
+	// recurring events contribute the occurrences that start within it.
+	ListEvents(ctx context.Context, start, end time.Time) ([]Booking, error)
+}
+
+// newCalendarProvider builds the CalendarProvider selected by
+// config.Provider, defaulting to Google Calendar for existing deployments
+// that don't set it.
+func newCalendarProvider(config CalendarConfig) (CalendarProvider, error) {
+	switch config.Provider {
+	case "", "google":
+		return newGoogleProvider(config), nil
+	case "caldav":
+		return newCalDAVProvider(config)
+	default:
+		return nil, fmt.Errorf("unknown calendar provider %q", config.Provider)
+	}
+}
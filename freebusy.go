@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// FreeSlot is a contiguous window of time within operating hours that has
+// no Booking covering it.
+type FreeSlot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// freeBusyHandler serves /freebusy?from=&to=&duration=, returning the free
+// slots within the configured operating hours that are at least `duration`
+// long.
+func freeBusyHandler(t *template.Template, cache *CalendarCache, config CalendarConfig) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		from := time.Now()
+		if s := r.URL.Query().Get("from"); s != "" {
+			if parsed, err := time.Parse("2006-01-02", s); err == nil {
+				from = parsed
+			}
+		}
+
+		to := from.AddDate(0, 0, 7)
+		if s := r.URL.Query().Get("to"); s != "" {
+			if parsed, err := time.Parse("2006-01-02", s); err == nil {
+				to = parsed
+			}
+		}
+
+		duration := 60 * time.Minute
+		if s := r.URL.Query().Get("duration"); s != "" {
+			if parsed, err := time.ParseDuration(s); err == nil {
+				duration = parsed
+			}
+		}
+
+		all, lastFetch, err := cache.Bookings()
+		if err != nil && lastFetch.IsZero() {
+			slog.Error("Failed to get calendar data", "error", err)
+			http.Error(rw, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		bookings := filterBookingsInWindow(all, from, to.AddDate(0, 0, 1))
+
+		slots := freeSlotsInRange(bookings, from, to, config.UtilizationConfig, duration)
+
+		if wantsJSON(r) {
+			rw.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(rw).Encode(slots); err != nil {
+				slog.Error("Failed to encode JSON response", "error", err)
+				http.Error(rw, "Internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		rw.Header().Set("Content-Type", "text/html")
+		data := struct {
+			From     time.Time
+			To       time.Time
+			Duration time.Duration
+			Slots    []FreeSlot
+		}{From: from, To: to, Duration: duration, Slots: slots}
+
+		if err := t.ExecuteTemplate(rw, "freebusy.html", data); err != nil {
+			slog.Error("Failed to execute templates", "error", err)
+			http.Error(rw, "Internal server error", http.StatusInternalServerError)
+		}
+	}
+}
+
+// freeSlotsInRange finds the gaps between bookings, per day, within
+// [config.StartHour, config.EndHour), for every day in [from, to], and
+// returns the ones at least minDuration long.
+func freeSlotsInRange(bookings []Booking, from, to time.Time, config UtilizationConfig, minDuration time.Duration) []FreeSlot {
+	var slots []FreeSlot
+	loc := config.Location
+
+	for day := dateOnly(from, loc); !day.After(dateOnly(to, loc)); day = day.AddDate(0, 0, 1) {
+		dayStart := time.Date(day.Year(), day.Month(), day.Day(), config.StartHour, 0, 0, 0, loc)
+		dayEnd := time.Date(day.Year(), day.Month(), day.Day(), config.EndHour, 0, 0, 0, loc)
+
+		var busy []FreeSlot
+		for _, booking := range bookings {
+			start, end := booking.StartTime, booking.EndTime
+			if end.Before(dayStart) || start.After(dayEnd) {
+				continue
+			}
+			if start.Before(dayStart) {
+				start = dayStart
+			}
+			if end.After(dayEnd) {
+				end = dayEnd
+			}
+			if end.After(start) {
+				busy = append(busy, FreeSlot{Start: start, End: end})
+			}
+		}
+		busy = mergeSlots(busy)
+
+		cursor := dayStart
+		for _, b := range busy {
+			if b.Start.After(cursor) {
+				slots = append(slots, FreeSlot{Start: cursor, End: b.Start})
+			}
+			if b.End.After(cursor) {
+				cursor = b.End
+			}
+		}
+		if dayEnd.After(cursor) {
+			slots = append(slots, FreeSlot{Start: cursor, End: dayEnd})
+		}
+	}
+
+	var filtered []FreeSlot
+	for _, slot := range slots {
+		if slot.End.Sub(slot.Start) >= minDuration {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
+// mergeSlots sorts and coalesces overlapping or touching intervals.
+func mergeSlots(slots []FreeSlot) []FreeSlot {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+
+	merged := []FreeSlot{slots[0]}
+	for _, cur := range slots[1:] {
+		last := &merged[len(merged)-1]
+		if cur.Start.After(last.End) {
+			merged = append(merged, cur)
+			continue
+		}
+		if cur.End.After(last.End) {
+			last.End = cur.End
+		}
+	}
+	return merged
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFreeSlotsInRange_RespectsFacilityTimezoneAcrossDST(t *testing.T) {
+	// from/to arrive as bare UTC midnights (as produced by time.Parse on
+	// a ?from=&to= query param); the day boundaries used to compute free
+	// slots must still be drawn in the facility's timezone, spanning the
+	// 2026-03-29 spring-forward transition.
+	config := UtilizationConfig{StartHour: 9, EndHour: 17, Location: london}
+	from := utc(2026, 3, 29, 0, 0)
+	to := utc(2026, 3, 29, 0, 0)
+
+	// A booking 09:00-10:00 BST (08:00-09:00 UTC).
+	bookings := []Booking{
+		{ID: "1", StartTime: utc(2026, 3, 29, 8, 0), EndTime: utc(2026, 3, 29, 9, 0)},
+	}
+
+	slots := freeSlotsInRange(bookings, from, to, config, 30*time.Minute)
+	if len(slots) != 1 {
+		t.Fatalf("expected 1 free slot, got %d: %+v", len(slots), slots)
+	}
+	wantStart := time.Date(2026, 3, 29, 10, 0, 0, 0, london)
+	wantEnd := time.Date(2026, 3, 29, 17, 0, 0, 0, london)
+	if !slots[0].Start.Equal(wantStart) || !slots[0].End.Equal(wantEnd) {
+		t.Errorf("expected slot %s-%s, got %s-%s", wantStart, wantEnd, slots[0].Start, slots[0].End)
+	}
+}
+
+func TestSameDay_ConvertsToFacilityTimezone(t *testing.T) {
+	// 23:30 UTC on 2026-07-12 is 00:30 BST on 2026-07-13: it must match
+	// the 2026-07-13 /day.ics download, not 2026-07-12.
+	date, err := time.Parse("2006-01-02", "2026-07-13")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sameDay(utc(2026, 7, 12, 23, 30), date, london) != true {
+		t.Error("expected 23:30 UTC on 2026-07-12 to match 2026-07-13 in Europe/London")
+	}
+	if sameDay(utc(2026, 7, 12, 20, 0), date, london) != false {
+		t.Error("expected 20:00 UTC on 2026-07-12 to remain on 2026-07-12 in Europe/London")
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+//go:embed credentials.json
+var credentialsData []byte
+
+//go:embed token.json
+var tokenData []byte
+
+// GoogleProvider implements CalendarProvider against the Google Calendar
+// API, authenticating with an embedded OAuth2 client secret and refresh
+// token.
+type GoogleProvider struct {
+	calendarID string
+}
+
+func newGoogleProvider(config CalendarConfig) *GoogleProvider {
+	return &GoogleProvider{calendarID: config.GoogleCalendarID}
+}
+
+func (p *GoogleProvider) ListEvents(ctx context.Context, start, end time.Time) ([]Booking, error) {
+	slog.Info("Starting calendar data retrieval", "calendar_id", p.calendarID, "start_date", start)
+
+	// Check if credentials data is available
+	if len(credentialsData) == 0 {
+		return nil, fmt.Errorf("credentials.json not found or empty. Please ensure credentials.json is available in the project root")
+	}
+	slog.Info("Credentials data loaded", "size", len(credentialsData))
+
+	// Create OAuth2 config from embedded credentials
+	oauthConfig, err := google.ConfigFromJSON(credentialsData, calendar.CalendarReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+	}
+	slog.Info("OAuth2 config created successfully")
+
+	// Check if token data is available
+	if len(tokenData) == 0 {
+		return nil, fmt.Errorf("token.json not found or empty. Please ensure token.json is available in the project root")
+	}
+	slog.Info("Token data loaded", "size", len(tokenData))
+
+	// Load token from embedded data
+	var tok oauth2.Token
+	if err := json.Unmarshal(tokenData, &tok); err != nil {
+		return nil, fmt.Errorf("unable to parse token: %v", err)
+	}
+	slog.Info("Token parsed successfully", "expiry", tok.Expiry)
+
+	// Create calendar service
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(oauthConfig.Client(ctx, &tok)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Calendar client: %v", err)
+	}
+	slog.Info("Calendar service created successfully")
+
+	slog.Info("Fetching calendar events", "start", start, "end", end)
+
+	// SingleEvents(false) so recurring bookings come back as a single master
+	// event with a Recurrence rule instead of pre-expanded instances; we
+	// expand it ourselves in parseBookings so EXDATEs and durations stay
+	// under our control.
+	events, err := srv.Events.List(p.calendarID).
+		ShowDeleted(false).
+		SingleEvents(false).
+		TimeMin(start.Format(time.RFC3339)).
+		TimeMax(end.Format(time.RFC3339)).
+		MaxResults(100).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve events: %v", err)
+	}
+	slog.Info("Calendar events retrieved", "count", len(events.Items))
+
+	bookings := parseBookings(events.Items, start, end)
+	slog.Info("Bookings parsed", "count", len(bookings))
+
+	return bookings, nil
+}
+
+func parseBookings(events []*calendar.Event, windowStart, windowEnd time.Time) []Booking {
+	var bookings []Booking
+
+	for _, item := range events {
+		if item.Start == nil || item.End == nil {
+			continue
+		}
+
+		var startTime, endTime time.Time
+		var err error
+
+		// Parse start time
+		if item.Start.DateTime != "" {
+			startTime, err = time.Parse(time.RFC3339, item.Start.DateTime)
+		} else {
+			startTime, err = time.Parse("2006-01-02", item.Start.Date)
+		}
+		if err != nil {
+			continue
+		}
+
+		// Parse end time
+		if item.End.DateTime != "" {
+			endTime, err = time.Parse(time.RFC3339, item.End.DateTime)
+		} else {
+			endTime, err = time.Parse("2006-01-02", item.End.Date)
+		}
+		if err != nil {
+			continue
+		}
+
+		title := item.Summary
+		if title == "" {
+			title = "(No title)"
+		}
+
+		if len(item.Recurrence) > 0 {
+			rruleLines, exdates := splitRecurrence(item.Recurrence)
+			bookings = append(bookings, expandRecurrence(item.Id, title, startTime, endTime, rruleLines, exdates, windowStart, windowEnd)...)
+			continue
+		}
+
+		bookings = append(bookings, Booking{
+			ID:        item.Id,
+			Title:     title,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Duration:  endTime.Sub(startTime),
+		})
+	}
+
+	return bookings
+}
+
+// splitRecurrence separates a calendar.Event's Recurrence lines (RRULE,
+// EXDATE, RDATE, ...) into the RRULEs to expand and the EXDATE exclusions to
+// apply. RDATE is not yet supported.
+func splitRecurrence(lines []string) (rruleLines []string, exdates []time.Time) {
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "RRULE:"):
+			rruleLines = append(rruleLines, line)
+		case strings.HasPrefix(line, "EXDATE"):
+			exdates = append(exdates, parseEXDATE(line)...)
+		}
+	}
+	return rruleLines, exdates
+}
+
+func parseEXDATE(line string) []time.Time {
+	tzid, value := icsTZIDAndValue(line)
+
+	var loc *time.Location
+	if tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	var dates []time.Time
+	for _, raw := range strings.Split(value, ",") {
+		if loc != nil {
+			if t, err := time.ParseInLocation("20060102T150405", raw, loc); err == nil {
+				dates = append(dates, t)
+				continue
+			}
+		}
+		if t, err := parseICSTime(raw); err == nil {
+			dates = append(dates, t)
+		}
+	}
+	return dates
+}
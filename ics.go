@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ICSEvent is the per-VEVENT data passed to the booking.ics template.
+type ICSEvent struct {
+	UID             string
+	Summary         string
+	DTStart         string
+	DTEnd           string
+	DTStamp         string
+	ReminderMinutes int
+}
+
+func toICSEvent(b Booking, reminderMinutes int) ICSEvent {
+	uid := b.ID
+	if uid == "" {
+		uid = fmt.Sprintf("%d", b.StartTime.Unix())
+	}
+
+	return ICSEvent{
+		UID:             uid + "@cardinhamtennis",
+		Summary:         escapeICSText(b.Title),
+		DTStart:         b.StartTime.UTC().Format("20060102T150405Z"),
+		DTEnd:           b.EndTime.UTC().Format("20060102T150405Z"),
+		DTStamp:         time.Now().UTC().Format("20060102T150405Z"),
+		ReminderMinutes: reminderMinutes,
+	}
+}
+
+// escapeICSText escapes a string for use in an RFC 5545 TEXT value
+// (SUMMARY, DESCRIPTION, ...): backslash, semicolon, and comma are
+// backslash-escaped, and newlines become the literal two-character
+// sequence "\n", since a raw newline would terminate the content line.
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// bookingICSHandler serves /booking.ics?id=..., downloading a single
+// booking as a VCALENDAR.
+func bookingICSHandler(icsTmpl *template.Template, cache *CalendarCache, config CalendarConfig) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(rw, "missing id parameter", http.StatusBadRequest)
+			return
+		}
+
+		booking, err := findBookingByID(cache, id)
+		if err != nil {
+			slog.Error("Failed to look up booking", "error", err, "id", id)
+			http.Error(rw, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if booking == nil {
+			http.NotFound(rw, r)
+			return
+		}
+
+		writeICS(rw, icsTmpl, "booking.ics", []ICSEvent{toICSEvent(*booking, config.UtilizationConfig.ReminderMinutes)})
+	}
+}
+
+// dayICSHandler serves /day.ics?date=YYYY-MM-DD, downloading every booking
+// on that day as a single VCALENDAR.
+func dayICSHandler(icsTmpl *template.Template, cache *CalendarCache, config CalendarConfig) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		date, err := time.Parse("2006-01-02", r.URL.Query().Get("date"))
+		if err != nil {
+			http.Error(rw, "invalid or missing date parameter, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+
+		all, lastFetch, err := cache.Bookings()
+		if err != nil && lastFetch.IsZero() {
+			slog.Error("Failed to get calendar data", "error", err)
+			http.Error(rw, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		var events []ICSEvent
+		for _, booking := range all {
+			if sameDay(booking.StartTime, date, config.UtilizationConfig.Location) {
+				events = append(events, toICSEvent(booking, config.UtilizationConfig.ReminderMinutes))
+			}
+		}
+
+		writeICS(rw, icsTmpl, "day.ics", events)
+	}
+}
+
+func writeICS(rw http.ResponseWriter, icsTmpl *template.Template, filename string, events []ICSEvent) {
+	rw.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename=%q`, filename))
+
+	data := struct{ Events []ICSEvent }{Events: events}
+	if err := icsTmpl.ExecuteTemplate(rw, "booking.ics", data); err != nil {
+		slog.Error("Failed to execute ICS template", "error", err)
+		http.Error(rw, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// findBookingByID looks up a cached booking by ID.
+func findBookingByID(cache *CalendarCache, id string) (*Booking, error) {
+	bookings, lastFetch, err := cache.Bookings()
+	if err != nil && lastFetch.IsZero() {
+		return nil, fmt.Errorf("unable to retrieve events: %v", err)
+	}
+
+	for _, booking := range bookings {
+		if booking.ID == id {
+			return &booking, nil
+		}
+	}
+	return nil, nil
+}
+
+// sameDay reports whether t, converted into loc, falls on the same calendar
+// day as date (the UTC-dated value parsed from a ?date=YYYY-MM-DD query
+// parameter), so a booking doesn't land in the adjacent day's download just
+// because t's embedded offset differs from the facility's timezone.
+func sameDay(t, date time.Time, loc *time.Location) bool {
+	t = t.In(loc)
+	return t.Year() == date.Year() && t.Month() == date.Month() && t.Day() == date.Day()
+}
@@ -10,39 +10,41 @@ import (
 	"net/http"
 	"os"
 	"runtime/debug"
+	texttemplate "text/template"
 	"time"
 
 	"github.com/apex/gateway/v2"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
-	"google.golang.org/api/calendar/v3"
-	"google.golang.org/api/option"
 )
 
 //go:embed templates
 var tmpl embed.FS
 
-//go:embed credentials.json
-var credentialsData []byte
-
-//go:embed token.json
-var tokenData []byte
-
 type CalendarConfig struct {
-	GoogleCalendarID  string            `json:"google_calendar_id"`
-	CredentialsFile   string            `json:"credentials_file"`
-	TokenFile         string            `json:"token_file"`
-	UtilizationConfig UtilizationConfig `json:"utilization_config"`
+	Provider            string            `json:"provider"` // "google" (default) or "caldav"
+	GoogleCalendarID    string            `json:"google_calendar_id"`
+	CredentialsFile     string            `json:"credentials_file"`
+	TokenFile           string            `json:"token_file"`
+	CalDAV              CalDAVConfig      `json:"caldav"`
+	CacheRefreshMinutes int               `json:"cache_refresh_minutes"` // How often to refresh the calendar cache. Default: 5
+	UtilizationConfig   UtilizationConfig `json:"utilization_config"`
 }
 
 type UtilizationConfig struct {
-	StartHour       int  `json:"start_hour"`        // Default: 6 (6 AM)
-	EndHour         int  `json:"end_hour"`          // Default: 18 (6 PM)
-	ShowDailyStats  bool `json:"show_daily_stats"`  // Default: true
-	ShowWeeklyStats bool `json:"show_weekly_stats"` // Default: true
+	StartHour       int    `json:"start_hour"`        // Default: 6 (6 AM)
+	EndHour         int    `json:"end_hour"`          // Default: 18 (6 PM)
+	ShowDailyStats  bool   `json:"show_daily_stats"`  // Default: true
+	ShowWeeklyStats bool   `json:"show_weekly_stats"` // Default: true
+	ReminderMinutes int    `json:"reminder_minutes"`  // VALARM lead time on downloaded .ics files. Default: 30
+	Timezone        string `json:"timezone"`          // IANA zone the facility operates in. Default: Europe/London
+
+	// Location is resolved from Timezone once at startup and used for all
+	// day/week bucketing, so DST transitions and viewer timezone don't
+	// shift which day or week a booking falls into.
+	Location *time.Location `json:"-"`
 }
 
 type Booking struct {
+	ID        string
 	Title     string
 	StartTime time.Time
 	EndTime   time.Time
@@ -78,11 +80,25 @@ type PageData struct {
 	TotalBookings  int
 	TotalHours     float64
 	AvgUtilization float64
+	LastFetch      time.Time
 }
 
 func main() {
 	commit, _ := GitCommit()
 
+	config := loadCalendarConfig()
+
+	provider, err := newCalendarProvider(config)
+	if err != nil {
+		slog.Error("Failed to set up calendar provider", "error", err)
+		return
+	}
+
+	cache := NewCalendarCache(provider, cacheRefreshInterval(config))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.Start(ctx)
+
 	t, err := template.New("base").Funcs(template.FuncMap{
 		"formatTime": func(t time.Time) string {
 			if t.Hour() == 0 && t.Minute() == 0 {
@@ -114,6 +130,12 @@ func main() {
 		return
 	}
 
+	icsTmpl, err := texttemplate.ParseFS(tmpl, "templates/*.ics")
+	if err != nil {
+		slog.Error("Failed to parse ICS templates", "error", err)
+		return
+	}
+
 	http.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
 		chosenDate := time.Now()
 		inputDate := r.URL.Query().Get("date")
@@ -123,49 +145,9 @@ func main() {
 			}
 		}
 
-		// Load configuration
-		config := loadCalendarConfig()
-
-		// Get calendar data with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		// Create a channel for the result
-		resultChan := make(chan struct {
-			bookings    []Booking
-			dailyStats  []DayStats
-			weeklyStats []WeekStats
-			err         error
-		}, 1)
-
-		// Run calendar data retrieval in a goroutine
-		go func() {
-			bookings, dailyStats, weeklyStats, err := getCalendarData(config, chosenDate)
-			resultChan <- struct {
-				bookings    []Booking
-				dailyStats  []DayStats
-				weeklyStats []WeekStats
-				err         error
-			}{bookings, dailyStats, weeklyStats, err}
-		}()
-
-		// Wait for result or timeout
-		var bookings []Booking
-		var dailyStats []DayStats
-		var weeklyStats []WeekStats
-		var err error
-
-		select {
-		case result := <-resultChan:
-			bookings = result.bookings
-			dailyStats = result.dailyStats
-			weeklyStats = result.weeklyStats
-			err = result.err
-		case <-ctx.Done():
-			err = fmt.Errorf("calendar data retrieval timed out after 10 seconds")
-		}
+		bookings, dailyStats, weeklyStats, lastFetch, err := calendarDataFromCache(cache, config, chosenDate)
 
-		if err != nil {
+		if err != nil && lastFetch.IsZero() {
 			slog.Error("Failed to get calendar data", "error", err)
 			// Return a user-friendly error page instead of 500
 			rw.Header().Set("Content-Type", "text/html")
@@ -226,16 +208,32 @@ func main() {
 			TotalBookings:  totalBookings,
 			TotalHours:     totalHours,
 			AvgUtilization: avgUtilization,
+			LastFetch:      lastFetch,
 		}
 
-		rw.Header().Set("Content-Type", "text/html")
-		err = t.ExecuteTemplate(rw, "index.html", pageData)
-		if err != nil {
-			slog.Error("Failed to execute templates", "error", err)
-			http.Error(rw, "Internal server error", http.StatusInternalServerError)
+		switch {
+		case wantsJSON(r):
+			rw.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(rw).Encode(pageData); err != nil {
+				slog.Error("Failed to encode JSON response", "error", err)
+				http.Error(rw, "Internal server error", http.StatusInternalServerError)
+			}
+		case wantsPlainText(r):
+			rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			renderPlainText(rw, pageData)
+		default:
+			rw.Header().Set("Content-Type", "text/html")
+			if err := t.ExecuteTemplate(rw, "index.html", pageData); err != nil {
+				slog.Error("Failed to execute templates", "error", err)
+				http.Error(rw, "Internal server error", http.StatusInternalServerError)
+			}
 		}
 	})
 
+	http.HandleFunc("/freebusy", freeBusyHandler(t, cache, config))
+	http.HandleFunc("/booking.ics", bookingICSHandler(icsTmpl, cache, config))
+	http.HandleFunc("/day.ics", dayICSHandler(icsTmpl, cache, config))
+
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
 
 	if _, ok := os.LookupEnv("AWS_LAMBDA_FUNCTION_NAME"); ok {
@@ -252,152 +250,57 @@ func main() {
 	slog.Error("error listening", "error", err)
 }
 
-func getCalendarData(config CalendarConfig, chosenDate time.Time) ([]Booking, []DayStats, []WeekStats, error) {
-	slog.Info("Starting calendar data retrieval", "calendar_id", config.GoogleCalendarID, "start_date", chosenDate)
-
-	// Check if credentials data is available
-	if len(credentialsData) == 0 {
-		return nil, nil, nil, fmt.Errorf("credentials.json not found or empty. Please ensure credentials.json is available in the project root")
-	}
-	slog.Info("Credentials data loaded", "size", len(credentialsData))
-
-	// Create OAuth2 config from embedded credentials
-	oauthConfig, err := google.ConfigFromJSON(credentialsData, calendar.CalendarReadonlyScope)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
-	}
-	slog.Info("OAuth2 config created successfully")
-
-	// Check if token data is available
-	if len(tokenData) == 0 {
-		return nil, nil, nil, fmt.Errorf("token.json not found or empty. Please ensure token.json is available in the project root")
-	}
-	slog.Info("Token data loaded", "size", len(tokenData))
-
-	// Load token from embedded data
-	var tok oauth2.Token
-	if err := json.Unmarshal(tokenData, &tok); err != nil {
-		return nil, nil, nil, fmt.Errorf("unable to parse token: %v", err)
-	}
-	slog.Info("Token parsed successfully", "expiry", tok.Expiry)
-
-	// Create calendar service
-	ctx := context.Background()
-	srv, err := calendar.NewService(ctx, option.WithHTTPClient(oauthConfig.Client(ctx, &tok)))
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("unable to retrieve Calendar client: %v", err)
-	}
-	slog.Info("Calendar service created successfully")
+// calendarDataFromCache serves the next 30 days from chosenDate out of the
+// cache instead of calling the calendar provider, so a slow or failing
+// upstream never blocks a request. A non-nil error is only fatal to the
+// caller when lastFetch is zero, i.e. the cache has never had a successful
+// fetch; otherwise it's stale-but-usable data and the error is informational.
+func calendarDataFromCache(cache *CalendarCache, config CalendarConfig, chosenDate time.Time) (bookings []Booking, dailyStats []DayStats, weeklyStats []WeekStats, lastFetch time.Time, err error) {
+	all, lastFetch, err := cache.Bookings()
 
-	// Get events for the next 30 days from chosen date
 	startTime := chosenDate
 	endTime := startTime.AddDate(0, 0, 30)
-	slog.Info("Fetching calendar events", "start", startTime, "end", endTime)
-
-	events, err := srv.Events.List(config.GoogleCalendarID).
-		ShowDeleted(false).
-		SingleEvents(true).
-		OrderBy("startTime").
-		TimeMin(startTime.Format(time.RFC3339)).
-		TimeMax(endTime.Format(time.RFC3339)).
-		MaxResults(100).
-		Do()
-
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("unable to retrieve events: %v", err)
-	}
-	slog.Info("Calendar events retrieved", "count", len(events.Items))
-
-	// Parse events into bookings
-	bookings := parseBookings(events.Items)
-	slog.Info("Bookings parsed", "count", len(bookings))
-
-	// Calculate statistics
-	var dailyStats []DayStats
-	var weeklyStats []WeekStats
+	bookings = filterBookingsInWindow(all, startTime, endTime)
 
 	if config.UtilizationConfig.ShowDailyStats {
 		dailyStats = calculateDailyStats(bookings, config.UtilizationConfig)
-		slog.Info("Daily stats calculated", "count", len(dailyStats))
 	}
 
 	if config.UtilizationConfig.ShowWeeklyStats {
 		weeklyStats = calculateWeeklyStats(bookings, config.UtilizationConfig)
-		slog.Info("Weekly stats calculated", "count", len(weeklyStats))
 	}
 
-	slog.Info("Calendar data retrieval completed successfully")
-	return bookings, dailyStats, weeklyStats, nil
+	return bookings, dailyStats, weeklyStats, lastFetch, err
 }
 
-func parseBookings(events []*calendar.Event) []Booking {
-	var bookings []Booking
-
-	for _, item := range events {
-		if item.Start == nil || item.End == nil {
-			continue
-		}
-
-		var startTime, endTime time.Time
-		var err error
-
-		// Parse start time
-		if item.Start.DateTime != "" {
-			startTime, err = time.Parse(time.RFC3339, item.Start.DateTime)
-		} else {
-			startTime, err = time.Parse("2006-01-02", item.Start.Date)
-		}
-		if err != nil {
-			continue
-		}
-
-		// Parse end time
-		if item.End.DateTime != "" {
-			endTime, err = time.Parse(time.RFC3339, item.End.DateTime)
-		} else {
-			endTime, err = time.Parse("2006-01-02", item.End.Date)
-		}
-		if err != nil {
-			continue
-		}
-
-		title := item.Summary
-		if title == "" {
-			title = "(No title)"
-		}
-
-		duration := endTime.Sub(startTime)
-
-		bookings = append(bookings, Booking{
-			Title:     title,
-			StartTime: startTime,
-			EndTime:   endTime,
-			Duration:  duration,
-		})
+// cacheRefreshInterval returns how often the CalendarCache should refresh,
+// defaulting to 5 minutes.
+func cacheRefreshInterval(config CalendarConfig) time.Duration {
+	if config.CacheRefreshMinutes <= 0 {
+		return 5 * time.Minute
 	}
-
-	return bookings
+	return time.Duration(config.CacheRefreshMinutes) * time.Minute
 }
 
 func calculateDailyStats(bookings []Booking, config UtilizationConfig) []DayStats {
-	// Group bookings by day
+	// Group bookings by day, in the facility's configured timezone
 	dayMap := make(map[string][]Booking)
 
 	for _, booking := range bookings {
-		dateKey := booking.StartTime.Format("2006-01-02")
+		dateKey := booking.StartTime.In(config.Location).Format("2006-01-02")
 		dayMap[dateKey] = append(dayMap[dateKey], booking)
 	}
 
 	var dailyStats []DayStats
 
 	for dateKey, dayBookings := range dayMap {
-		date, _ := time.Parse("2006-01-02", dateKey)
+		date, _ := time.ParseInLocation("2006-01-02", dateKey, config.Location)
 
 		// Calculate total hours for the day
 		var totalHours float64
 		for _, booking := range dayBookings {
 			// Only count hours within the facility's operating hours
-			bookingHours := calculateBookingHoursInRange(booking, config.StartHour, config.EndHour)
+			bookingHours := calculateBookingHoursInRange(booking, config.StartHour, config.EndHour, config.Location)
 			totalHours += bookingHours
 		}
 
@@ -416,24 +319,26 @@ func calculateDailyStats(bookings []Booking, config UtilizationConfig) []DayStat
 	return dailyStats
 }
 
-func calculateBookingHoursInRange(booking Booking, startHour, endHour int) float64 {
-	// If it's an all-day event, count full operating hours
-	if booking.StartTime.Hour() == 0 && booking.StartTime.Minute() == 0 {
+func calculateBookingHoursInRange(booking Booking, startHour, endHour int, loc *time.Location) float64 {
+	// All-day events are stored as UTC midnight-to-midnight, so check
+	// that against the UTC time before converting into the facility's
+	// timezone below - a non-UTC offset would otherwise shift the UTC
+	// midnight off Hour()==0 and make an all-day event look timed.
+	if booking.StartTime.UTC().Hour() == 0 && booking.StartTime.UTC().Minute() == 0 {
 		return float64(endHour - startHour)
 	}
 
-	// Calculate the effective start and end times within operating hours
-	effectiveStart := booking.StartTime
-	effectiveEnd := booking.EndTime
+	effectiveStart := booking.StartTime.In(loc)
+	effectiveEnd := booking.EndTime.In(loc)
 
 	// Adjust start time if it's before operating hours
 	if effectiveStart.Hour() < startHour {
-		effectiveStart = time.Date(effectiveStart.Year(), effectiveStart.Month(), effectiveStart.Day(), startHour, 0, 0, 0, effectiveStart.Location())
+		effectiveStart = time.Date(effectiveStart.Year(), effectiveStart.Month(), effectiveStart.Day(), startHour, 0, 0, 0, loc)
 	}
 
 	// Adjust end time if it's after operating hours
 	if effectiveEnd.Hour() > endHour {
-		effectiveEnd = time.Date(effectiveEnd.Year(), effectiveEnd.Month(), effectiveEnd.Day(), endHour, 0, 0, 0, effectiveEnd.Location())
+		effectiveEnd = time.Date(effectiveEnd.Year(), effectiveEnd.Month(), effectiveEnd.Day(), endHour, 0, 0, 0, loc)
 	}
 
 	// Calculate duration in hours
@@ -453,8 +358,8 @@ func calculateWeeklyStats(bookings []Booking, config UtilizationConfig) []WeekSt
 	weekMap := make(map[string][]Booking)
 
 	for _, booking := range bookings {
-		// Get the start of the week (Monday)
-		weekStart := getWeekStart(booking.StartTime)
+		// Get the start of the week (Monday), in the facility's timezone
+		weekStart := getWeekStart(booking.StartTime.In(config.Location))
 		weekKey := weekStart.Format("2006-01-02")
 		weekMap[weekKey] = append(weekMap[weekKey], booking)
 	}
@@ -462,7 +367,7 @@ func calculateWeeklyStats(bookings []Booking, config UtilizationConfig) []WeekSt
 	var weeklyStats []WeekStats
 
 	for weekKey, weekBookings := range weekMap {
-		weekStart, _ := time.Parse("2006-01-02", weekKey)
+		weekStart, _ := time.ParseInLocation("2006-01-02", weekKey, config.Location)
 		weekEnd := weekStart.AddDate(0, 0, 6)
 
 		// Calculate daily stats for this week
@@ -490,6 +395,14 @@ func calculateWeeklyStats(bookings []Booking, config UtilizationConfig) []WeekSt
 	return weeklyStats
 }
 
+// dateOnly truncates t to midnight in loc, so every handler that buckets
+// bookings by calendar day (daily/weekly stats, /freebusy, /day.ics) agrees
+// on which day that is, regardless of the zone t happens to be expressed in.
+func dateOnly(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
 func getWeekStart(date time.Time) time.Time {
 	// Get Monday of the week
 	weekday := date.Weekday()
@@ -507,6 +420,9 @@ func loadCalendarConfig() CalendarConfig {
 		if err == nil {
 			var config CalendarConfig
 			if json.Unmarshal(data, &config) == nil {
+				if config.Provider == "" {
+					config.Provider = "google"
+				}
 				// Set defaults for utilization config if not provided
 				if config.UtilizationConfig.StartHour == 0 {
 					config.UtilizationConfig.StartHour = 6
@@ -518,6 +434,13 @@ func loadCalendarConfig() CalendarConfig {
 					config.UtilizationConfig.ShowDailyStats = true
 					config.UtilizationConfig.ShowWeeklyStats = true
 				}
+				if config.UtilizationConfig.ReminderMinutes == 0 {
+					config.UtilizationConfig.ReminderMinutes = 30
+				}
+				if config.UtilizationConfig.Timezone == "" {
+					config.UtilizationConfig.Timezone = "Europe/London"
+				}
+				config.UtilizationConfig.Location = resolveTimezone(config.UtilizationConfig.Timezone)
 				return config
 			}
 		}
@@ -554,7 +477,13 @@ func loadCalendarConfig() CalendarConfig {
 		}
 	}
 
+	timezone := os.Getenv("UTILIZATION_TIMEZONE")
+	if timezone == "" {
+		timezone = "Europe/London"
+	}
+
 	return CalendarConfig{
+		Provider:         "google",
 		GoogleCalendarID: calendarID,
 		CredentialsFile:  credentialsFile,
 		TokenFile:        tokenFile,
@@ -563,10 +492,25 @@ func loadCalendarConfig() CalendarConfig {
 			EndHour:         endHour,
 			ShowDailyStats:  true,
 			ShowWeeklyStats: true,
+			ReminderMinutes: 30,
+			Timezone:        timezone,
+			Location:        resolveTimezone(timezone),
 		},
 	}
 }
 
+// resolveTimezone loads an IANA location, falling back to UTC (and logging)
+// if the name is invalid so a typo in config.json degrades gracefully
+// instead of crashing the server.
+func resolveTimezone(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		slog.Error("Invalid timezone, falling back to UTC", "timezone", name, "error", err)
+		return time.UTC
+	}
+	return loc
+}
+
 func GitCommit() (commit string, dirty bool) {
 	bi, ok := debug.ReadBuildInfo()
 	if !ok {
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+var london = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}()
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestCalculateDailyStats_SpringForward(t *testing.T) {
+	// Clocks in Europe/London go forward at 01:00 UTC on 2026-03-29. A
+	// 09:00 BST (08:00 UTC) booking must bucket under 2026-03-29 local,
+	// not the UTC date it happens to share that day.
+	config := UtilizationConfig{StartHour: 6, EndHour: 18, Location: london}
+	bookings := []Booking{
+		{ID: "1", StartTime: utc(2026, 3, 29, 8, 0), EndTime: utc(2026, 3, 29, 9, 0)},
+	}
+
+	stats := calculateDailyStats(bookings, config)
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 day bucket, got %d", len(stats))
+	}
+	if got := stats[0].Date.Format("2006-01-02"); got != "2026-03-29" {
+		t.Errorf("expected booking bucketed on 2026-03-29, got %s", got)
+	}
+}
+
+func TestCalculateDailyStats_FallBack(t *testing.T) {
+	// Clocks in Europe/London go back at 02:00 BST on 2026-10-25. A
+	// booking just after midnight UTC should still land on 2026-10-25
+	// local (00:30 UTC == 01:30 BST, before the transition).
+	config := UtilizationConfig{StartHour: 6, EndHour: 18, Location: london}
+	bookings := []Booking{
+		{ID: "1", StartTime: utc(2026, 10, 25, 0, 30), EndTime: utc(2026, 10, 25, 1, 30)},
+	}
+
+	stats := calculateDailyStats(bookings, config)
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 day bucket, got %d", len(stats))
+	}
+	if got := stats[0].Date.Format("2006-01-02"); got != "2026-10-25" {
+		t.Errorf("expected booking bucketed on 2026-10-25, got %s", got)
+	}
+}
+
+func TestCalculateDailyStats_AllDayStraddlesMidnight(t *testing.T) {
+	// An all-day event is stored as midnight-to-midnight UTC. In
+	// Europe/London during BST that's 01:00-01:00 local, crossing local
+	// midnight; it must still bucket under its start date with the full
+	// operating hours.
+	config := UtilizationConfig{StartHour: 6, EndHour: 18, Location: london}
+	booking := Booking{
+		ID:        "1",
+		StartTime: utc(2026, 7, 13, 0, 0),
+		EndTime:   utc(2026, 7, 14, 0, 0),
+		Duration:  24 * time.Hour,
+	}
+
+	stats := calculateDailyStats([]Booking{booking}, config)
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 day bucket, got %d", len(stats))
+	}
+	if got := stats[0].Date.Format("2006-01-02"); got != "2026-07-13" {
+		t.Errorf("expected all-day booking bucketed on 2026-07-13, got %s", got)
+	}
+	if got := stats[0].TotalHours; got != 12 {
+		t.Errorf("expected full operating hours (12) for all-day booking, got %v", got)
+	}
+}
+
+func TestDateOnly_ConvertsToFacilityTimezone(t *testing.T) {
+	// 23:30 UTC on 2026-07-12 is 00:30 BST on 2026-07-13: dateOnly must
+	// bucket it under the facility-local day, not the UTC one.
+	got := dateOnly(utc(2026, 7, 12, 23, 30), london)
+	if got.Format("2006-01-02") != "2026-07-13" {
+		t.Errorf("expected 2026-07-13, got %s", got.Format("2006-01-02"))
+	}
+}
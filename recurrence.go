@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// expandRecurrence materializes the occurrences of a recurring event that
+// start inside [windowStart, windowEnd] (both ends inclusive) into concrete
+// Bookings, honouring any
+// EXDATE exclusions. Each occurrence keeps the master event's duration and
+// is given an ID derived from the master's uid plus its occurrence time, so
+// individual instances can still be looked up (e.g. for .ics download).
+func expandRecurrence(uid, title string, start, end time.Time, rruleLines []string, exdates []time.Time, windowStart, windowEnd time.Time) []Booking {
+	var bookings []Booking
+	duration := end.Sub(start)
+
+	for _, line := range rruleLines {
+		opt, err := rrule.StrToROption(strings.TrimPrefix(line, "RRULE:"))
+		if err != nil {
+			continue
+		}
+		opt.Dtstart = start
+
+		rule, err := rrule.NewRRule(*opt)
+		if err != nil {
+			continue
+		}
+
+		for _, occurrence := range rule.Between(windowStart, windowEnd, true) {
+			if isExcluded(occurrence, exdates) {
+				continue
+			}
+			bookings = append(bookings, Booking{
+				ID:        fmt.Sprintf("%s-%s", uid, occurrence.UTC().Format("20060102T150405Z")),
+				Title:     title,
+				StartTime: occurrence,
+				EndTime:   occurrence.Add(duration),
+				Duration:  duration,
+			})
+		}
+	}
+
+	return bookings
+}
+
+func isExcluded(t time.Time, exdates []time.Time) bool {
+	for _, ex := range exdates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
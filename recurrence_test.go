@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandRecurrence_WeeklyWithTZIDExdate(t *testing.T) {
+	// Mirrors a real Google Calendar export for a timed weekly event: a
+	// 3-occurrence weekly RRULE with one EXDATE expressed in the
+	// facility's local timezone rather than UTC.
+	start := time.Date(2026, 7, 6, 9, 0, 0, 0, london) // Monday, BST
+	end := start.Add(time.Hour)
+
+	rruleLines := []string{"RRULE:FREQ=WEEKLY;COUNT=3"}
+	exdates := parseEXDATE("EXDATE;TZID=Europe/London:20260713T090000")
+	if len(exdates) != 1 {
+		t.Fatalf("expected 1 exdate parsed, got %d", len(exdates))
+	}
+
+	windowStart := start.AddDate(0, 0, -1)
+	windowEnd := start.AddDate(0, 0, 21)
+
+	bookings := expandRecurrence("uid1", "Coaching", start, end, rruleLines, exdates, windowStart, windowEnd)
+
+	if len(bookings) != 2 {
+		t.Fatalf("expected 2 occurrences after excluding 2026-07-13, got %d: %+v", len(bookings), bookings)
+	}
+
+	wantDates := []string{"2026-07-06", "2026-07-20"}
+	for i, b := range bookings {
+		if got := b.StartTime.In(london).Format("2006-01-02"); got != wantDates[i] {
+			t.Errorf("occurrence %d: expected date %s, got %s", i, wantDates[i], got)
+		}
+	}
+}
+
+func TestIsExcluded(t *testing.T) {
+	t1 := time.Date(2026, 7, 13, 9, 0, 0, 0, london)
+	t2 := time.Date(2026, 7, 13, 8, 0, 0, 0, time.UTC) // same instant as t1
+
+	if !isExcluded(t2, []time.Time{t1}) {
+		t.Error("expected matching instant in a different zone to be excluded")
+	}
+	if isExcluded(time.Date(2026, 7, 20, 9, 0, 0, 0, london), []time.Time{t1}) {
+		t.Error("expected a different occurrence to not be excluded")
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+)
+
+// wantsJSON reports whether the client asked for a machine-readable
+// response via the Accept header.
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// wantsPlainText reports whether the client is a terminal tool (curl, wget)
+// or explicitly asked for text/plain, in which case an ASCII table reads
+// better than HTML.
+func wantsPlainText(r *http.Request) bool {
+	if strings.Contains(r.Header.Get("Accept"), "text/plain") {
+		return true
+	}
+	ua := strings.ToLower(r.Header.Get("User-Agent"))
+	return strings.Contains(ua, "curl") || strings.Contains(ua, "wget")
+}
+
+// renderPlainText writes an ASCII table of daily/weekly utilization and
+// bookings, so the page is usable straight from `curl`.
+func renderPlainText(w io.Writer, data PageData) {
+	fmt.Fprintf(w, "Cardinham Tennis Utilization - %s\n\n", data.ChosenDate.Format("Mon Jan 2 2006"))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "DAILY STATS")
+	fmt.Fprintln(tw, "DATE\tHOURS\tUTILIZATION")
+	for _, day := range data.DailyStats {
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f%%\n", day.Date.Format("Mon Jan 2"), day.TotalHours, day.Utilization)
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "WEEKLY STATS")
+	fmt.Fprintln(tw, "WEEK START\tWEEK END\tHOURS\tUTILIZATION")
+	for _, week := range data.WeeklyStats {
+		fmt.Fprintf(tw, "%s\t%s\t%.1f\t%.1f%%\n", week.WeekStart.Format("Mon Jan 2"), week.WeekEnd.Format("Mon Jan 2"), week.TotalHours, week.Utilization)
+	}
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "BOOKINGS")
+	fmt.Fprintln(tw, "START\tEND\tTITLE")
+	for _, booking := range data.Bookings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", booking.StartTime.Format("Mon Jan 2 15:04"), booking.EndTime.Format("15:04"), booking.Title)
+	}
+
+	tw.Flush()
+}